@@ -0,0 +1,210 @@
+// Package nat is a convenience package for manipulation of strings describing
+// network ports.
+package nat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PortSpecTemplate is the expected format for port specifications
+	PortSpecTemplate = "ip:hostPort:containerPort"
+)
+
+// Port is a string containing port number and protocol in the format "80/tcp"
+type Port string
+
+// NewPort creates a new instance of a Port given a protocol and port number
+func NewPort(proto, port string) (Port, error) {
+	portInt, err := strconv.Atoi(port)
+	if err != nil {
+		return "", err
+	}
+	if portInt < 0 || portInt > 65535 {
+		return "", fmt.Errorf("invalid port: %s", port)
+	}
+	return Port(fmt.Sprintf("%d/%s", portInt, proto)), nil
+}
+
+// ParsePort parses the port number string and returns an int
+func ParsePort(rawPort string) (int, error) {
+	if len(rawPort) == 0 {
+		return 0, nil
+	}
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	return int(port), nil
+}
+
+// Proto returns the protocol of a Port
+func (p Port) Proto() string {
+	proto, _ := SplitProtoPort(string(p))
+	return proto
+}
+
+// Port returns the port number of a Port
+func (p Port) Port() string {
+	_, port := SplitProtoPort(string(p))
+	return port
+}
+
+// Int returns the port number of a Port as an int
+func (p Port) Int() int {
+	portStr := p.Port()
+	if len(portStr) == 0 {
+		return 0
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return 0
+	}
+	return int(port)
+}
+
+// SplitProtoPort splits a port in the format of port/proto and returns the
+// proto and the port
+func SplitProtoPort(rawPort string) (string, string) {
+	parts := strings.Split(rawPort, "/")
+	l := len(parts)
+	if len(rawPort) == 0 || l == 0 || len(parts[0]) == 0 {
+		return "", ""
+	}
+	if l == 1 {
+		return "tcp", rawPort
+	}
+	if len(parts[1]) == 0 {
+		return "tcp", parts[0]
+	}
+	return parts[1], parts[0]
+}
+
+func validateProto(proto string) bool {
+	for _, availableProto := range []string{"tcp", "udp"} {
+		if availableProto == proto {
+			return true
+		}
+	}
+	return false
+}
+
+// PortSet is a collection of structs indexed by Port
+type PortSet map[Port]struct{}
+
+// PortBinding represents a binding between a Host IP address and a Host Port
+type PortBinding struct {
+	HostIP   string
+	HostPort string
+}
+
+// PortMap is a collection of PortBinding indexed by Port
+type PortMap map[Port][]PortBinding
+
+// ParsePortSpecs receives port specs in the format of ip:public:private/proto
+// and parses these in to the internal types
+func ParsePortSpecs(ports []string) (map[Port]struct{}, map[Port][]PortBinding, error) {
+	var (
+		exposedPorts = make(map[Port]struct{}, len(ports))
+		bindings     = make(map[Port][]PortBinding)
+	)
+	for _, rawPort := range ports {
+		proto := "tcp"
+		if i := strings.LastIndex(rawPort, "/"); i != -1 {
+			proto = rawPort[i+1:]
+			rawPort = rawPort[:i]
+		}
+		if !strings.Contains(rawPort, ":") {
+			rawPort = fmt.Sprintf("::%s", rawPort)
+		} else if len(strings.Split(rawPort, ":")) == 2 {
+			rawPort = fmt.Sprintf(":%s", rawPort)
+		}
+
+		parts, err := splitParts(rawPort)
+		if err != nil {
+			return nil, nil, err
+		}
+		ip, hostPort, containerPort := parts[0], parts[1], parts[2]
+
+		if !validateProto(strings.ToLower(proto)) {
+			return nil, nil, fmt.Errorf("invalid proto: %s", proto)
+		}
+
+		startPort, endPort, err := parsePortRange(containerPort)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid containerPort: %s", containerPort)
+		}
+
+		var startHostPort, endHostPort uint64
+		if len(hostPort) > 0 {
+			startHostPort, endHostPort, err = parsePortRange(hostPort)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid hostPort: %s", hostPort)
+			}
+		}
+
+		if hostPort != "" && (endPort-startPort) != (endHostPort-startHostPort) {
+			return nil, nil, fmt.Errorf("invalid ranges specified for container and host Ports")
+		}
+
+		for i := int(0); i <= int(endPort-startPort); i++ {
+			containerPort := strconv.FormatUint(startPort+uint64(i), 10)
+			var port Port
+			port, err = NewPort(strings.ToLower(proto), containerPort)
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, exists := exposedPorts[port]; !exists {
+				exposedPorts[port] = struct{}{}
+			}
+
+			if len(hostPort) > 0 {
+				hostPort := strconv.FormatUint(startHostPort+uint64(i), 10)
+				binding := PortBinding{
+					HostIP:   ip,
+					HostPort: hostPort,
+				}
+				bindings[port] = append(bindings[port], binding)
+			}
+		}
+	}
+	return exposedPorts, bindings, nil
+}
+
+func splitParts(rawport string) ([3]string, error) {
+	parts := strings.Split(rawport, ":")
+	n := len(parts)
+	containerPort := parts[n-1]
+	switch n {
+	case 1:
+		return [3]string{"", "", containerPort}, nil
+	case 2:
+		return [3]string{"", parts[0], containerPort}, nil
+	case 3:
+		return [3]string{parts[0], parts[1], containerPort}, nil
+	default:
+		return [3]string{}, fmt.Errorf("invalid port format: %s", rawport)
+	}
+}
+
+func parsePortRange(rawPort string) (uint64, uint64, error) {
+	if !strings.Contains(rawPort, "-") {
+		start, err := strconv.ParseUint(rawPort, 10, 16)
+		return start, start, err
+	}
+	parts := strings.SplitN(rawPort, "-", 2)
+	start, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := strconv.ParseUint(parts[1], 10, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("invalid range: %s", rawPort)
+	}
+	return start, end, nil
+}
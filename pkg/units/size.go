@@ -0,0 +1,42 @@
+// Package units provides helper functions for parsing human-readable size
+// strings such as "64m" or "1g" into a number of bytes.
+package units
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var sizeSuffixes = map[byte]int64{
+	'b': 1,
+	'k': 1024,
+	'm': 1024 * 1024,
+	'g': 1024 * 1024 * 1024,
+	't': 1024 * 1024 * 1024 * 1024,
+}
+
+// RAMInBytes parses a human-readable size string (e.g. "64", "64b", "64m",
+// "1g") and returns the equivalent number of bytes. A bare number is
+// interpreted as bytes.
+func RAMInBytes(size string) (int64, error) {
+	if size == "" {
+		return 0, fmt.Errorf("invalid size: %q", size)
+	}
+
+	suffix := strings.ToLower(size[len(size)-1:])
+	numPart := size
+	multiplier, isSuffix := sizeSuffixes[suffix[0]]
+	if isSuffix {
+		numPart = size[:len(size)-1]
+	} else {
+		multiplier = 1
+	}
+
+	value, err := strconv.ParseFloat(numPart, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size: %q", size)
+	}
+
+	return int64(value * float64(multiplier)), nil
+}
@@ -0,0 +1,165 @@
+package runconfig
+
+import (
+	"encoding/json"
+
+	"github.com/docker/docker/pkg/nat"
+)
+
+// Command represents the command to run in a container, which can be
+// unmarshaled from either a JSON string or a JSON array of strings.
+type Command struct {
+	parts []string
+}
+
+// NewCommand creates a Command from a list of arguments.
+func NewCommand(parts ...string) *Command {
+	return &Command{parts: parts}
+}
+
+// Len returns the number of parts in the command.
+func (e *Command) Len() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.parts)
+}
+
+// Slice returns the command as a slice of strings.
+func (e *Command) Slice() []string {
+	if e == nil {
+		return nil
+	}
+	return e.parts
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Command) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(e.parts)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either a JSON string
+// or a JSON array of strings for backward compatibility with older API
+// payloads.
+func (e *Command) UnmarshalJSON(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+
+	p := make([]string, 0, 1)
+	if err := json.Unmarshal(b, &p); err != nil {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		p = append(p, s)
+	}
+	e.parts = p
+	return nil
+}
+
+// Entrypoint represents the entrypoint to run in a container, with the same
+// marshaling semantics as Command.
+type Entrypoint struct {
+	parts []string
+}
+
+// NewEntrypoint creates an Entrypoint from a list of arguments.
+func NewEntrypoint(parts ...string) *Entrypoint {
+	return &Entrypoint{parts: parts}
+}
+
+// Len returns the number of parts in the entrypoint.
+func (e *Entrypoint) Len() int {
+	if e == nil {
+		return 0
+	}
+	return len(e.parts)
+}
+
+// Slice returns the entrypoint as a slice of strings.
+func (e *Entrypoint) Slice() []string {
+	if e == nil {
+		return nil
+	}
+	return e.parts
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Entrypoint) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(e.parts)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *Entrypoint) UnmarshalJSON(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+
+	p := make([]string, 0, 1)
+	if err := json.Unmarshal(b, &p); err != nil {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		p = append(p, s)
+	}
+	e.parts = p
+	return nil
+}
+
+// Config contains the configuration data about a container. It is the
+// portion of a container's information that is portable between hosts.
+type Config struct {
+	Hostname        string
+	Domainname      string
+	User            string
+	AttachStdin     bool
+	AttachStdout    bool
+	AttachStderr    bool
+	ExposedPorts    nat.PortSet
+	Tty             bool
+	OpenStdin       bool
+	StdinOnce       bool
+	Env             []string
+	Cmd             *Command
+	Image           string
+	Volumes         map[string]struct{}
+	WorkingDir      string
+	Entrypoint      *Entrypoint
+	NetworkDisabled bool
+	OnBuild         []string
+	// Labels holds the set of metadata key/value pairs attached to the
+	// container, supplied via --label and --label-file and merged with
+	// the image's own labels.
+	Labels     map[string]string
+	MacAddress string
+}
+
+// ContainerConfigWrapper is a Config wrapper that holds the container Config
+// (portable), the corresponding HostConfig (non-portable), and the legacy
+// top-level fields older API versions sent instead of nesting them under
+// HostConfig. See version.go for how these are reconciled per-version.
+type ContainerConfigWrapper struct {
+	*Config
+	HostConfig *HostConfig `json:"HostConfig,omitempty"`
+
+	// MemoryLegacy, MemorySwapLegacy, CPUSharesLegacy and CpusetCpusLegacy
+	// are where API versions before 1.19 placed what is now HostConfig
+	// resource configuration.
+	MemoryLegacy     *int64  `json:"Memory,omitempty"`
+	MemorySwapLegacy *int64  `json:"MemorySwap,omitempty"`
+	CPUSharesLegacy  *int64  `json:"CpuShares,omitempty"`
+	CpusetCpusLegacy *string `json:"CpusetCpus,omitempty"`
+
+	// VolumesFromLegacy and LinksLegacy are where API versions before 1.20
+	// placed what is now HostConfig.VolumesFrom and HostConfig.Links.
+	VolumesFromLegacy string   `json:"VolumesFrom,omitempty"`
+	LinksLegacy       []string `json:"Links,omitempty"`
+}
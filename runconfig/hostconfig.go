@@ -0,0 +1,31 @@
+package runconfig
+
+import "github.com/docker/docker/pkg/nat"
+
+// HostConfig contains the non-portable, host-specific configuration of a
+// container (bind mounts, resource limits, network mode, and so on).
+type HostConfig struct {
+	Binds           []string
+	Mounts          []Mount           `json:",omitempty"`
+	Tmpfs           map[string]string `json:",omitempty"`
+	ShmSize         int64             `json:",omitempty"`
+	ContainerIDFile string
+	Memory          int64
+	MemorySwap      int64
+	CPUShares       int64
+	CpusetCpus      string
+	Privileged      bool
+	PortBindings    nat.PortMap
+	Links           []string
+	PublishAllPorts bool
+	Dns             []string
+	DnsSearch       []string
+	ExtraHosts      []string
+	VolumesFrom     []string
+	NetworkMode     string
+	CapAdd          []string
+	CapDrop         []string
+	ReadonlyRootfs  bool
+	SecurityOpt     []string
+	CgroupParent    string
+}
@@ -0,0 +1,138 @@
+package runconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRunLabels(t *testing.T) {
+	cases := []struct {
+		args     string
+		expected map[string]string
+	}{
+		{"", nil},
+		{"--label foo=bar", map[string]string{"foo": "bar"}},
+		{"--label foo=bar --label baz=qux", map[string]string{"foo": "bar", "baz": "qux"}},
+		{"--label foo=", map[string]string{"foo": ""}},
+		{"--label foo", map[string]string{"foo": ""}},
+		{"--label foo=bar --label foo=baz", map[string]string{"foo": "baz"}},
+		{"-l foo=bar", map[string]string{"foo": "bar"}},
+	}
+
+	for _, c := range cases {
+		config, _ := mustParse(t, c.args)
+		if !reflect.DeepEqual(config.Labels, c.expected) {
+			t.Fatalf("args %q: expected labels %v, got %v", c.args, c.expected, config.Labels)
+		}
+	}
+}
+
+func TestParseRunLabelFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "label-file-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "labels.txt")
+	contents := "# a comment\nfoo=bar\n\nbaz=qux\n"
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, _ := mustParse(t, "--label-file "+path)
+	expected := map[string]string{"foo": "bar", "baz": "qux"}
+	if !reflect.DeepEqual(config.Labels, expected) {
+		t.Fatalf("expected labels %v, got %v", expected, config.Labels)
+	}
+
+	// a --label flag overrides the same key from an earlier --label-file
+	config, _ = mustParse(t, "--label-file "+path+" --label foo=override")
+	expected = map[string]string{"foo": "override", "baz": "qux"}
+	if !reflect.DeepEqual(config.Labels, expected) {
+		t.Fatalf("expected labels %v, got %v", expected, config.Labels)
+	}
+
+	// but command-line order wins regardless of flag vs file: a
+	// --label-file appearing after --label overrides it in turn
+	config, _ = mustParse(t, "--label foo=flag "+"--label-file "+path)
+	expected = map[string]string{"foo": "bar", "baz": "qux"}
+	if !reflect.DeepEqual(config.Labels, expected) {
+		t.Fatalf("expected labels %v, got %v", expected, config.Labels)
+	}
+}
+
+func TestParseLabelQuoting(t *testing.T) {
+	// Labels aren't shell-quoted by the parser itself (the shell already
+	// strips quotes before docker ever sees the argument); a value that
+	// still contains quote characters at this layer is kept verbatim.
+	k, v, err := parseLabel(`foo="bar baz"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k != "foo" || v != `"bar baz"` {
+		t.Fatalf("expected quotes to be preserved verbatim, got %q=%q", k, v)
+	}
+
+	// only the first "=" separates key from value, so an "=" embedded in
+	// a quoted value doesn't get mistaken for a second label.
+	k, v, err = parseLabel(`query="a=b"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k != "query" || v != `"a=b"` {
+		t.Fatalf("expected embedded '=' to stay part of the value, got %q=%q", k, v)
+	}
+}
+
+func TestCompareLabels(t *testing.T) {
+	base := &Config{Labels: map[string]string{"foo": "bar"}}
+	same := &Config{Labels: map[string]string{"foo": "bar"}}
+	differentValue := &Config{Labels: map[string]string{"foo": "baz"}}
+	differentKey := &Config{Labels: map[string]string{"other": "bar"}}
+	extraKey := &Config{Labels: map[string]string{"foo": "bar", "extra": "1"}}
+
+	if !Compare(base, same) {
+		t.Fatalf("Compare should return true, Labels are identical")
+	}
+	if Compare(base, differentValue) {
+		t.Fatalf("Compare should return false, label values differ")
+	}
+	if Compare(base, differentKey) {
+		t.Fatalf("Compare should return false, label keys differ")
+	}
+	if Compare(base, extraKey) {
+		t.Fatalf("Compare should return false, label sets differ in size")
+	}
+}
+
+func TestMergeLabels(t *testing.T) {
+	imageConf := &Config{Labels: map[string]string{"com.example.vendor": "acme", "version": "1.0"}}
+	userConf := &Config{Labels: map[string]string{"version": "2.0"}}
+
+	if err := Merge(userConf, imageConf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"com.example.vendor": "acme", "version": "2.0"}
+	if !reflect.DeepEqual(userConf.Labels, expected) {
+		t.Fatalf("expected merged labels %v, got %v", expected, userConf.Labels)
+	}
+}
+
+func TestMergeLabelsNilUser(t *testing.T) {
+	imageConf := &Config{Labels: map[string]string{"com.example.vendor": "acme"}}
+	userConf := &Config{}
+
+	if err := Merge(userConf, imageConf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"com.example.vendor": "acme"}
+	if !reflect.DeepEqual(userConf.Labels, expected) {
+		t.Fatalf("expected merged labels %v, got %v", expected, userConf.Labels)
+	}
+}
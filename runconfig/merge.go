@@ -0,0 +1,133 @@
+package runconfig
+
+import (
+	"strings"
+
+	"github.com/docker/docker/pkg/nat"
+)
+
+// Merge merges two Config, the image configuration (defaults coming from the
+// image) and the user supplied configuration. Anything the user explicitly
+// set takes precedence; the image's values are only used to fill in what the
+// user left unset. The result is written back into userConf.
+func Merge(userConf, imageConf *Config) error {
+	if userConf == nil || imageConf == nil {
+		return nil
+	}
+
+	if userConf.User == "" {
+		userConf.User = imageConf.User
+	}
+	if userConf.WorkingDir == "" {
+		userConf.WorkingDir = imageConf.WorkingDir
+	}
+
+	mergePorts(userConf, imageConf)
+	mergeEnv(userConf, imageConf)
+	mergeVolumes(userConf, imageConf)
+	mergeLabels(userConf, imageConf)
+
+	if userConf.Cmd.Len() == 0 {
+		userConf.Cmd = imageConf.Cmd
+	}
+	if userConf.Entrypoint.Len() == 0 {
+		userConf.Entrypoint = imageConf.Entrypoint
+	}
+
+	return nil
+}
+
+func mergePorts(userConf, imageConf *Config) {
+	if imageConf.ExposedPorts == nil {
+		return
+	}
+	if userConf.ExposedPorts == nil {
+		userConf.ExposedPorts = make(nat.PortSet)
+	}
+	for port := range imageConf.ExposedPorts {
+		userConf.ExposedPorts[port] = struct{}{}
+	}
+}
+
+// mergeEnv unions the two Env lists, with the user's value winning whenever
+// the same key is set on both sides (the same semantics used for Labels).
+func mergeEnv(userConf, imageConf *Config) {
+	userConf.Env = mergeKeyValueList(userConf.Env, imageConf.Env)
+}
+
+func mergeVolumes(userConf, imageConf *Config) {
+	if imageConf.Volumes == nil {
+		return
+	}
+	if userConf.Volumes == nil {
+		userConf.Volumes = make(map[string]struct{})
+	}
+	for v := range imageConf.Volumes {
+		userConf.Volumes[v] = struct{}{}
+	}
+}
+
+// mergeLabels unions labels from the image config into the user config, with
+// user-supplied values winning on key collision, mirroring mergeEnv.
+func mergeLabels(userConf, imageConf *Config) {
+	if imageConf.Labels == nil {
+		return
+	}
+	if userConf.Labels == nil {
+		userConf.Labels = make(map[string]string)
+	}
+	for k, v := range imageConf.Labels {
+		if _, exists := userConf.Labels[k]; !exists {
+			userConf.Labels[k] = v
+		}
+	}
+}
+
+// mergeKeyValueList merges two KEY=VALUE lists, keeping every key from
+// "user" as-is and appending any key from "image" that user doesn't already
+// define.
+func mergeKeyValueList(user, image []string) []string {
+	keys := make(map[string]struct{}, len(user))
+	for _, kv := range user {
+		keys[keyOf(kv)] = struct{}{}
+	}
+	merged := append([]string{}, user...)
+	for _, kv := range image {
+		if _, exists := keys[keyOf(kv)]; exists {
+			continue
+		}
+		merged = append(merged, kv)
+	}
+	return merged
+}
+
+func keyOf(kv string) string {
+	if i := strings.Index(kv, "="); i >= 0 {
+		return kv[:i]
+	}
+	return kv
+}
+
+// MergeHostConfig merges the image's HostConfig defaults into the user's
+// HostConfig. As with Merge, anything the user explicitly set wins; for
+// Tmpfs this means image entries are kept unless the user mounts a tmpfs at
+// the same target.
+func MergeHostConfig(userConf, imageConf *HostConfig) error {
+	if userConf == nil || imageConf == nil {
+		return nil
+	}
+
+	if imageConf.Tmpfs == nil {
+		return nil
+	}
+	if userConf.Tmpfs == nil {
+		userConf.Tmpfs = make(map[string]string)
+	}
+	for target, options := range imageConf.Tmpfs {
+		if _, exists := userConf.Tmpfs[target]; !exists {
+			userConf.Tmpfs[target] = options
+		}
+	}
+
+	return nil
+}
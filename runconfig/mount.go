@@ -0,0 +1,231 @@
+package runconfig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MountType represents the type of a mount added via --mount.
+type MountType string
+
+// Available mount types.
+const (
+	MountTypeBind   MountType = "bind"
+	MountTypeVolume MountType = "volume"
+	MountTypeTmpfs  MountType = "tmpfs"
+)
+
+// Propagation represents the propagation of a bind mount.
+type Propagation string
+
+// Available propagation modes for a bind mount.
+const (
+	PropagationRPrivate Propagation = "rprivate"
+	PropagationPrivate  Propagation = "private"
+	PropagationRShared  Propagation = "rshared"
+	PropagationShared   Propagation = "shared"
+	PropagationRSlave   Propagation = "rslave"
+	PropagationSlave    Propagation = "slave"
+)
+
+// BindOptions holds the options specific to a bind mount (--mount
+// type=bind).
+type BindOptions struct {
+	Propagation Propagation `json:",omitempty"`
+	// Selinux is the SELinux relabeling mode for the bind mount, "z" to
+	// share the label with other containers or "Z" to relabel it
+	// exclusively for this container, matching the -v Z/z suffixes.
+	Selinux string `json:",omitempty"`
+}
+
+// VolumeOptions holds the options specific to a named volume mount
+// (--mount type=volume).
+type VolumeOptions struct {
+	Driver     string            `json:",omitempty"`
+	DriverOpts map[string]string `json:",omitempty"`
+}
+
+// TmpfsOptions holds the options specific to a tmpfs mount (--mount
+// type=tmpfs).
+type TmpfsOptions struct {
+	SizeBytes int64  `json:",omitempty"`
+	Mode      uint32 `json:",omitempty"`
+}
+
+// Mount represents a single entry parsed from --mount, describing where and
+// how something should be mounted into the container.
+type Mount struct {
+	Type          MountType
+	Source        string `json:",omitempty"`
+	Target        string
+	ReadOnly      bool           `json:",omitempty"`
+	BindOptions   *BindOptions   `json:",omitempty"`
+	VolumeOptions *VolumeOptions `json:",omitempty"`
+	TmpfsOptions  *TmpfsOptions  `json:",omitempty"`
+}
+
+var validPropagations = map[Propagation]bool{
+	PropagationRPrivate: true,
+	PropagationPrivate:  true,
+	PropagationRShared:  true,
+	PropagationShared:   true,
+	PropagationRSlave:   true,
+	PropagationSlave:    true,
+}
+
+// parseMountSpecs parses the list of raw --mount values into typed Mounts,
+// rejecting unknown keys, missing targets, and targets that collide with
+// each other or with -v binds.
+func parseMountSpecs(mounts []string, usedTargets map[string]bool) ([]Mount, error) {
+	var result []Mount
+
+	for _, spec := range mounts {
+		m, err := parseMountSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mount specification: %q: %v", spec, err)
+		}
+		if usedTargets[m.Target] {
+			return nil, fmt.Errorf("duplicate mount target: %s", m.Target)
+		}
+		usedTargets[m.Target] = true
+		result = append(result, *m)
+	}
+
+	return result, nil
+}
+
+func parseMountSpec(spec string) (*Mount, error) {
+	var (
+		m            Mount
+		roValues     []bool
+		volumeOpts   *VolumeOptions
+		propagation  Propagation
+		hasProp      bool
+		selinux      string
+		hasSelinux   bool
+		tmpfsSize    int64
+		hasTmpfsSize bool
+		tmpfsMode    uint32
+		hasTmpfsMode bool
+	)
+
+	for _, field := range strings.Split(spec, ",") {
+		if field == "" {
+			continue
+		}
+		var key, value string
+		if i := strings.Index(field, "="); i >= 0 {
+			key, value = field[:i], field[i+1:]
+		} else {
+			key = field
+		}
+
+		switch key {
+		case "type":
+			m.Type = MountType(value)
+		case "source", "src":
+			m.Source = value
+		case "target", "dst", "destination":
+			m.Target = value
+		case "readonly", "ro":
+			ro := true
+			if value != "" {
+				var err error
+				ro, err = strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for readonly: %s", value)
+				}
+			}
+			roValues = append(roValues, ro)
+			m.ReadOnly = ro
+		case "bind-propagation":
+			propagation = Propagation(value)
+			hasProp = true
+		case "selinux-label":
+			if value != "z" && value != "Z" {
+				return nil, fmt.Errorf("invalid selinux-label: %s", value)
+			}
+			selinux = value
+			hasSelinux = true
+		case "volume-driver":
+			if volumeOpts == nil {
+				volumeOpts = &VolumeOptions{}
+			}
+			volumeOpts.Driver = value
+		case "volume-opt":
+			if volumeOpts == nil {
+				volumeOpts = &VolumeOptions{}
+			}
+			k, v, err := parseLabel(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid volume-opt: %s", value)
+			}
+			if volumeOpts.DriverOpts == nil {
+				volumeOpts.DriverOpts = make(map[string]string)
+			}
+			volumeOpts.DriverOpts[k] = v
+		case "tmpfs-size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || size < 0 {
+				return nil, fmt.Errorf("invalid tmpfs-size: %s", value)
+			}
+			tmpfsSize = size
+			hasTmpfsSize = true
+		case "tmpfs-mode":
+			mode, err := strconv.ParseUint(value, 8, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tmpfs-mode: %s", value)
+			}
+			tmpfsMode = uint32(mode)
+			hasTmpfsMode = true
+		default:
+			return nil, fmt.Errorf("unknown mount option: %s", key)
+		}
+	}
+
+	if m.Target == "" {
+		return nil, fmt.Errorf("target is required")
+	}
+	for _, ro := range roValues {
+		if ro != roValues[0] {
+			return nil, fmt.Errorf("conflicting readonly options in mount specification")
+		}
+	}
+
+	switch m.Type {
+	case MountTypeBind:
+		if hasTmpfsSize || hasTmpfsMode || volumeOpts != nil {
+			return nil, fmt.Errorf("tmpfs/volume options are not valid for a bind mount")
+		}
+		if m.Source == "" {
+			return nil, fmt.Errorf("source is required for a bind mount")
+		}
+		if hasProp {
+			if !validPropagations[propagation] {
+				return nil, fmt.Errorf("invalid bind-propagation: %s", propagation)
+			}
+		}
+		if hasProp || hasSelinux {
+			m.BindOptions = &BindOptions{Propagation: propagation, Selinux: selinux}
+		}
+	case MountTypeVolume:
+		if hasTmpfsSize || hasTmpfsMode || hasProp || hasSelinux {
+			return nil, fmt.Errorf("bind/tmpfs options are not valid for a volume mount")
+		}
+		m.VolumeOptions = volumeOpts
+	case MountTypeTmpfs:
+		if volumeOpts != nil || hasProp || hasSelinux || m.Source != "" {
+			return nil, fmt.Errorf("source/bind/volume options are not valid for a tmpfs mount")
+		}
+		if hasTmpfsSize || hasTmpfsMode {
+			m.TmpfsOptions = &TmpfsOptions{SizeBytes: tmpfsSize, Mode: tmpfsMode}
+		}
+	case "":
+		return nil, fmt.Errorf("type is required")
+	default:
+		return nil, fmt.Errorf("unknown mount type: %s", m.Type)
+	}
+
+	return &m, nil
+}
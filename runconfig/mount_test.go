@@ -0,0 +1,154 @@
+package runconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestParseRunMountBind(t *testing.T) {
+	_, hostConfig := mustParse(t, "--mount type=bind,source=/host,target=/container")
+	if len(hostConfig.Mounts) != 1 {
+		t.Fatalf("expected 1 mount, got %v", hostConfig.Mounts)
+	}
+	m := hostConfig.Mounts[0]
+	if m.Type != MountTypeBind || m.Source != "/host" || m.Target != "/container" || m.ReadOnly {
+		t.Fatalf("unexpected bind mount: %+v", m)
+	}
+}
+
+func TestParseRunMountBindReadOnlyAndPropagation(t *testing.T) {
+	_, hostConfig := mustParse(t, "--mount type=bind,source=/host,target=/container,readonly,bind-propagation=rshared")
+	m := hostConfig.Mounts[0]
+	if !m.ReadOnly {
+		t.Fatalf("expected readonly bind mount, got %+v", m)
+	}
+	if m.BindOptions == nil || m.BindOptions.Propagation != PropagationRShared {
+		t.Fatalf("expected rshared propagation, got %+v", m.BindOptions)
+	}
+}
+
+func TestParseRunMountVolume(t *testing.T) {
+	_, hostConfig := mustParse(t, "--mount type=volume,source=myvol,target=/data,volume-driver=local,volume-opt=o=bind")
+	m := hostConfig.Mounts[0]
+	if m.Type != MountTypeVolume || m.Source != "myvol" || m.Target != "/data" {
+		t.Fatalf("unexpected volume mount: %+v", m)
+	}
+	if m.VolumeOptions == nil || m.VolumeOptions.Driver != "local" || m.VolumeOptions.DriverOpts["o"] != "bind" {
+		t.Fatalf("unexpected volume options: %+v", m.VolumeOptions)
+	}
+}
+
+func TestParseRunMountTmpfs(t *testing.T) {
+	_, hostConfig := mustParse(t, "--mount type=tmpfs,target=/tmp,tmpfs-size=1024,tmpfs-mode=1777")
+	m := hostConfig.Mounts[0]
+	if m.Type != MountTypeTmpfs || m.Target != "/tmp" {
+		t.Fatalf("unexpected tmpfs mount: %+v", m)
+	}
+	if m.TmpfsOptions == nil || m.TmpfsOptions.SizeBytes != 1024 || m.TmpfsOptions.Mode != 01777 {
+		t.Fatalf("unexpected tmpfs options: %+v", m.TmpfsOptions)
+	}
+}
+
+func TestParseRunMountSelinuxLabel(t *testing.T) {
+	_, hostConfig := mustParse(t, "--mount type=bind,source=/host,target=/container,selinux-label=Z")
+	m := hostConfig.Mounts[0]
+	if m.BindOptions == nil || m.BindOptions.Selinux != "Z" {
+		t.Fatalf("expected exclusive Z selinux label, got %+v", m.BindOptions)
+	}
+
+	_, hostConfig = mustParse(t, "--mount type=bind,source=/host,target=/container,selinux-label=z")
+	m = hostConfig.Mounts[0]
+	if m.BindOptions == nil || m.BindOptions.Selinux != "z" {
+		t.Fatalf("expected shared z selinux label, got %+v", m.BindOptions)
+	}
+
+	cases := []string{
+		"type=bind,source=/host,target=/container,selinux-label=bogus",
+		"type=volume,source=myvol,target=/data,selinux-label=z",
+		"type=tmpfs,target=/tmp,selinux-label=z",
+	}
+	for _, c := range cases {
+		if _, _, err := parse(t, "--mount "+c); err == nil {
+			t.Fatalf("expected error for mount spec %q", c)
+		}
+	}
+}
+
+func TestDecodeContainerConfigMountsRoundTrip(t *testing.T) {
+	_, hostConfig := mustParse(t, "--mount type=bind,source=/host,target=/container,readonly,selinux-label=Z "+
+		"--mount type=volume,source=myvol,target=/data,volume-driver=local,volume-opt=o=bind "+
+		"--mount type=tmpfs,target=/tmp,tmpfs-size=1024,tmpfs-mode=1777")
+	if len(hostConfig.Mounts) != 3 {
+		t.Fatalf("expected 3 mounts, got %v", hostConfig.Mounts)
+	}
+
+	wrapper := ContainerConfigWrapper{Config: &Config{Image: "ubuntu"}, HostConfig: hostConfig}
+	b, err := json.Marshal(wrapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, decoded, err := DecodeContainerConfig(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Mounts) != 3 {
+		t.Fatalf("expected 3 mounts to survive the round trip, got %v", decoded.Mounts)
+	}
+
+	bind := decoded.Mounts[0]
+	if bind.Type != MountTypeBind || bind.Source != "/host" || bind.Target != "/container" || !bind.ReadOnly {
+		t.Fatalf("bind mount did not round-trip, got %+v", bind)
+	}
+	if bind.BindOptions == nil || bind.BindOptions.Selinux != "Z" {
+		t.Fatalf("expected selinux label to round-trip, got %+v", bind.BindOptions)
+	}
+
+	volume := decoded.Mounts[1]
+	if volume.Type != MountTypeVolume || volume.Source != "myvol" || volume.Target != "/data" {
+		t.Fatalf("volume mount did not round-trip, got %+v", volume)
+	}
+	if volume.VolumeOptions == nil || volume.VolumeOptions.Driver != "local" || volume.VolumeOptions.DriverOpts["o"] != "bind" {
+		t.Fatalf("volume options did not round-trip, got %+v", volume.VolumeOptions)
+	}
+
+	tmpfs := decoded.Mounts[2]
+	if tmpfs.Type != MountTypeTmpfs || tmpfs.Target != "/tmp" {
+		t.Fatalf("tmpfs mount did not round-trip, got %+v", tmpfs)
+	}
+	if tmpfs.TmpfsOptions == nil || tmpfs.TmpfsOptions.SizeBytes != 1024 || tmpfs.TmpfsOptions.Mode != 01777 {
+		t.Fatalf("tmpfs options did not round-trip, got %+v", tmpfs.TmpfsOptions)
+	}
+}
+
+func TestParseRunMountErrors(t *testing.T) {
+	cases := []string{
+		"type=bind,target=/container,unknown=1",
+		"type=bind,target=/container",
+		"type=bogus,target=/container",
+		"type=bind,source=/host",
+		"type=tmpfs,target=/tmp,readonly=true,ro=false",
+	}
+	for _, c := range cases {
+		if _, _, err := parse(t, "--mount "+c); err == nil {
+			t.Fatalf("expected error for mount spec %q", c)
+		}
+	}
+}
+
+func TestParseRunMountDuplicateTarget(t *testing.T) {
+	if _, _, err := parse(t, "-v /host:/data --mount type=volume,source=myvol,target=/data"); err == nil {
+		t.Fatalf("expected error for duplicate mount target")
+	}
+}
+
+// An anonymous -v volume occupies its container path just as much as a
+// host:container bind does, so it must also conflict with a --mount at the
+// same target.
+func TestParseRunMountDuplicateAnonymousVolumeTarget(t *testing.T) {
+	if _, _, err := parse(t, "-v /data --mount type=tmpfs,target=/data"); err == nil {
+		t.Fatalf("expected error when --mount collides with an anonymous -v target")
+	}
+}
@@ -0,0 +1,311 @@
+package runconfig
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// listOpts is a simple flag.Value implementation that collects repeated
+// occurrences of a flag into a slice, in the order they were given on the
+// command line.
+type listOpts struct {
+	values *[]string
+}
+
+func newListOpts(values *[]string) *listOpts {
+	return &listOpts{values: values}
+}
+
+func (o *listOpts) String() string {
+	if o.values == nil {
+		return ""
+	}
+	return strings.Join(*o.values, ",")
+}
+
+func (o *listOpts) Set(value string) error {
+	*o.values = append(*o.values, value)
+	return nil
+}
+
+// labelEntry is one --label or --label-file occurrence, recorded in the
+// order it appeared on the command line so that later entries, regardless
+// of which flag produced them, can override earlier ones.
+type labelEntry struct {
+	isFile bool
+	value  string
+}
+
+// labelOpts is a flag.Value that appends every --label/--label-file
+// occurrence it sees to a single shared slice, preserving their relative
+// command-line order across both flags.
+type labelOpts struct {
+	entries *[]labelEntry
+	isFile  bool
+}
+
+func newLabelOpts(entries *[]labelEntry, isFile bool) *labelOpts {
+	return &labelOpts{entries: entries, isFile: isFile}
+}
+
+func (o *labelOpts) String() string {
+	return ""
+}
+
+func (o *labelOpts) Set(value string) error {
+	*o.entries = append(*o.entries, labelEntry{isFile: o.isFile, value: value})
+	return nil
+}
+
+// parseRun parses the command line arguments for `docker run` (and the
+// container-creation portion of `docker create`) into a Config and
+// HostConfig. It returns the flag.FlagSet used, so that callers can inspect
+// which flags were explicitly set.
+func parseRun(args []string) (*Config, *HostConfig, *flag.FlagSet, error) {
+	cmd := flag.NewFlagSet("run", flag.ContinueOnError)
+	cmd.SetOutput(&nopWriter{})
+	cmd.Usage = func() {}
+
+	var (
+		flAttach       []string
+		flVolumes      []string
+		flLinks        []string
+		flEnv          []string
+		flLabelEntries []labelEntry
+		flMounts       []string
+		flTmpfs        []string
+		flShmSize      string
+		flDetach       bool
+		flRm           bool
+	)
+
+	cmd.Var(newListOpts(&flAttach), "a", "Attach to STDIN, STDOUT or STDERR")
+	cmd.Var(newListOpts(&flAttach), "attach", "Attach to STDIN, STDOUT or STDERR")
+	cmd.Var(newListOpts(&flVolumes), "v", "Bind mount a volume")
+	cmd.Var(newListOpts(&flVolumes), "volume", "Bind mount a volume")
+	cmd.Var(newListOpts(&flLinks), "link", "Add link to another container")
+	cmd.Var(newListOpts(&flEnv), "e", "Set environment variables")
+	cmd.Var(newListOpts(&flEnv), "env", "Set environment variables")
+	cmd.Var(newLabelOpts(&flLabelEntries, false), "l", "Set metadata on a container")
+	cmd.Var(newLabelOpts(&flLabelEntries, false), "label", "Set metadata on a container")
+	cmd.Var(newLabelOpts(&flLabelEntries, true), "label-file", "Read labels from a file")
+	cmd.Var(newListOpts(&flMounts), "mount", "Attach a filesystem mount to the container")
+	cmd.Var(newListOpts(&flTmpfs), "tmpfs", "Mount a tmpfs directory")
+	cmd.StringVar(&flShmSize, "shm-size", "", "Size of /dev/shm")
+	cmd.BoolVar(&flDetach, "d", false, "Run container in background")
+	cmd.BoolVar(&flDetach, "detach", false, "Run container in background")
+	cmd.BoolVar(&flRm, "rm", false, "Automatically remove the container when it exits")
+
+	if err := cmd.Parse(args); err != nil {
+		return nil, nil, cmd, err
+	}
+
+	var (
+		attachStdin, attachStdout, attachStderr bool
+	)
+	if len(flAttach) == 0 && !flDetach {
+		attachStdout = true
+		attachStderr = true
+	}
+	for _, attach := range flAttach {
+		switch strings.ToLower(attach) {
+		case "stdin":
+			attachStdin = true
+		case "stdout":
+			attachStdout = true
+		case "stderr":
+			attachStderr = true
+		default:
+			return nil, nil, cmd, fmt.Errorf("invalid stream specified with -a flag: %s", attach)
+		}
+	}
+	if flDetach && len(flAttach) > 0 {
+		return nil, nil, cmd, fmt.Errorf("conflicting options: -a and -d")
+	}
+	if flDetach && flRm {
+		return nil, nil, cmd, fmt.Errorf("conflicting options: -d and --rm")
+	}
+
+	binds, volumes, err := parseVolumes(flVolumes)
+	if err != nil {
+		return nil, nil, cmd, err
+	}
+
+	labels, err := parseLabels(flLabelEntries)
+	if err != nil {
+		return nil, nil, cmd, err
+	}
+
+	usedTargets := make(map[string]bool, len(binds)+len(volumes))
+	for _, bind := range binds {
+		usedTargets[strings.Split(bind, ":")[1]] = true
+	}
+	for v := range volumes {
+		usedTargets[v] = true
+	}
+	mounts, err := parseMountSpecs(flMounts, usedTargets)
+	if err != nil {
+		return nil, nil, cmd, err
+	}
+
+	tmpfs, err := parseTmpfs(flTmpfs, usedTargets)
+	if err != nil {
+		return nil, nil, cmd, err
+	}
+
+	shmSize, err := parseShmSize(flShmSize)
+	if err != nil {
+		return nil, nil, cmd, err
+	}
+
+	parsedArgs := cmd.Args()
+	if len(parsedArgs) == 0 {
+		return nil, nil, cmd, fmt.Errorf("image name is required")
+	}
+	image := parsedArgs[0]
+	runCmd := parsedArgs[1:]
+
+	config := &Config{
+		AttachStdin:  attachStdin,
+		AttachStdout: attachStdout,
+		AttachStderr: attachStderr,
+		Env:          flEnv,
+		Cmd:          NewCommand(runCmd...),
+		Image:        image,
+		Volumes:      volumes,
+		Labels:       labels,
+	}
+
+	hostConfig := &HostConfig{
+		Binds:   binds,
+		Mounts:  mounts,
+		Tmpfs:   tmpfs,
+		ShmSize: shmSize,
+		Links:   flLinks,
+	}
+
+	return config, hostConfig, cmd, nil
+}
+
+// parseVolumes splits the raw -v/--volume values into Binds (host:container
+// bind mounts) and Volumes (anonymous container volumes).
+func parseVolumes(volumeSpecs []string) ([]string, map[string]struct{}, error) {
+	var binds []string
+	volumes := make(map[string]struct{})
+
+	for _, spec := range volumeSpecs {
+		parts := strings.Split(spec, ":")
+		switch len(parts) {
+		case 1:
+			if parts[0] == "" || parts[0] == "/" || !strings.HasPrefix(parts[0], "/") {
+				return nil, nil, fmt.Errorf("invalid volume specification: %s", spec)
+			}
+			volumes[parts[0]] = struct{}{}
+		case 2, 3:
+			if parts[0] == "" || parts[1] == "" ||
+				!strings.HasPrefix(parts[0], "/") || !strings.HasPrefix(parts[1], "/") {
+				return nil, nil, fmt.Errorf("invalid volume specification: %s", spec)
+			}
+			if parts[1] == "/" {
+				return nil, nil, fmt.Errorf("invalid specification: destination can't be '/' in %q", spec)
+			}
+			if len(parts) == 3 {
+				mode := parts[2]
+				if !validBindMode(mode) {
+					return nil, nil, fmt.Errorf("invalid mode for volume specification: %s", spec)
+				}
+			}
+			binds = append(binds, spec)
+		default:
+			return nil, nil, fmt.Errorf("invalid volume specification: %s", spec)
+		}
+	}
+
+	return binds, volumes, nil
+}
+
+func validBindMode(mode string) bool {
+	switch mode {
+	case "ro", "rw", "roZ", "rwZ", "Z", "z":
+		return true
+	}
+	return false
+}
+
+// parseLabels builds the final label set from the --label KEY=VALUE and
+// --label-file <path> occurrences, applied in the exact order they appeared
+// on the command line: whichever comes last for a given key wins, whether
+// it came from a flag or a file. --label-file lines are "#"-comment and
+// blank-line tolerant; a key set by a later line in the same file wins over
+// an earlier line.
+func parseLabels(entries []labelEntry) (map[string]string, error) {
+	result := make(map[string]string)
+
+	for _, entry := range entries {
+		if entry.isFile {
+			fileLabels, err := parseLabelFile(entry.value)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range fileLabels {
+				result[k] = v
+			}
+			continue
+		}
+
+		k, v, err := parseLabel(entry.value)
+		if err != nil {
+			return nil, err
+		}
+		result[k] = v
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+func parseLabel(label string) (string, string, error) {
+	parts := strings.SplitN(label, "=", 2)
+	if parts[0] == "" {
+		return "", "", fmt.Errorf("invalid label %q: empty key", label)
+	}
+	if len(parts) == 1 {
+		return parts[0], "", nil
+	}
+	return parts[0], parts[1], nil
+}
+
+func parseLabelFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, err := parseLabel(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		result[k] = v
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
@@ -0,0 +1,102 @@
+package runconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/pkg/units"
+)
+
+// minShmSize is the smallest --shm-size docker will accept; anything
+// smaller isn't useful and is rejected up front rather than surfacing a
+// confusing mount failure later.
+const minShmSize = 4096
+
+var validTmpfsOptions = map[string]bool{
+	"rw":       true,
+	"ro":       true,
+	"noexec":   true,
+	"nosuid":   true,
+	"nodev":    true,
+	"exec":     true,
+	"suid":     true,
+	"dev":      true,
+	"relatime": true,
+}
+
+// parseTmpfs parses the repeatable --tmpfs <path>[:<options>] flag into a
+// path -> mount-options map, validating that each path is absolute, that no
+// path is used twice, and that it doesn't collide with a bind-mount target
+// from -v.
+func parseTmpfs(specs []string, usedTargets map[string]bool) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	tmpfs := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		var path, options string
+		if i := strings.Index(spec, ":"); i >= 0 {
+			path, options = spec[:i], spec[i+1:]
+		} else {
+			path = spec
+		}
+
+		if !strings.HasPrefix(path, "/") || path == "/" {
+			return nil, fmt.Errorf("invalid tmpfs path: %q must be an absolute path", path)
+		}
+		if usedTargets[path] {
+			return nil, fmt.Errorf("duplicate mount target: %s", path)
+		}
+		if _, exists := tmpfs[path]; exists {
+			return nil, fmt.Errorf("duplicate tmpfs target: %s", path)
+		}
+
+		if err := validateTmpfsOptions(options); err != nil {
+			return nil, err
+		}
+
+		tmpfs[path] = options
+		usedTargets[path] = true
+	}
+
+	return tmpfs, nil
+}
+
+func validateTmpfsOptions(options string) error {
+	if options == "" {
+		return nil
+	}
+	for _, opt := range strings.Split(options, ",") {
+		key := opt
+		if i := strings.Index(opt, "="); i >= 0 {
+			key = opt[:i]
+		}
+		switch key {
+		case "size", "mode", "uid", "gid":
+			continue
+		default:
+			if !validTmpfsOptions[key] {
+				return fmt.Errorf("invalid tmpfs option: %q", opt)
+			}
+		}
+	}
+	return nil
+}
+
+// parseShmSize parses the --shm-size flag, accepting human-readable sizes
+// such as "64m" or "1g", and rejects values docker considers too small to
+// be useful.
+func parseShmSize(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+	bytes, err := units.RAMInBytes(size)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --shm-size: %v", err)
+	}
+	if bytes < minShmSize {
+		return 0, fmt.Errorf("invalid --shm-size: %d is too small, minimum is %d bytes", bytes, minShmSize)
+	}
+	return bytes, nil
+}
@@ -0,0 +1,110 @@
+package runconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestParseRunTmpfs(t *testing.T) {
+	_, hostConfig := mustParse(t, "--tmpfs /run")
+	if opts, ok := hostConfig.Tmpfs["/run"]; !ok || opts != "" {
+		t.Fatalf("expected /run tmpfs with no options, got %v", hostConfig.Tmpfs)
+	}
+
+	_, hostConfig = mustParse(t, "--tmpfs /run:rw,noexec,nosuid,size=65536k")
+	if opts := hostConfig.Tmpfs["/run"]; opts != "rw,noexec,nosuid,size=65536k" {
+		t.Fatalf("expected options to round-trip, got %q", opts)
+	}
+
+	_, hostConfig = mustParse(t, "--tmpfs /run --tmpfs /run2:ro")
+	if len(hostConfig.Tmpfs) != 2 {
+		t.Fatalf("expected 2 tmpfs mounts, got %v", hostConfig.Tmpfs)
+	}
+}
+
+func TestParseRunTmpfsErrors(t *testing.T) {
+	cases := []string{
+		"relative",
+		"/",
+		"/run:bogus-option",
+	}
+	for _, c := range cases {
+		if _, _, err := parse(t, "--tmpfs "+c); err == nil {
+			t.Fatalf("expected error for --tmpfs %q", c)
+		}
+	}
+
+	if _, _, err := parse(t, "-v /host:/run --tmpfs /run"); err == nil {
+		t.Fatalf("expected error when --tmpfs collides with a -v bind target")
+	}
+
+	// An anonymous -v volume occupies its container path just as much as a
+	// host:container bind does, so --tmpfs must conflict with it too.
+	if _, _, err := parse(t, "-v /run --tmpfs /run"); err == nil {
+		t.Fatalf("expected error when --tmpfs collides with an anonymous -v target")
+	}
+}
+
+func TestParseRunShmSize(t *testing.T) {
+	_, hostConfig := mustParse(t, "--shm-size 64m")
+	if hostConfig.ShmSize != 64*1024*1024 {
+		t.Fatalf("expected 64m to be 67108864 bytes, got %d", hostConfig.ShmSize)
+	}
+
+	_, hostConfig = mustParse(t, "--shm-size 1g")
+	if hostConfig.ShmSize != 1024*1024*1024 {
+		t.Fatalf("expected 1g to be 1073741824 bytes, got %d", hostConfig.ShmSize)
+	}
+
+	if _, hostConfig := mustParse(t, ""); hostConfig.ShmSize != 0 {
+		t.Fatalf("expected default shm-size to be 0, got %d", hostConfig.ShmSize)
+	}
+}
+
+func TestParseRunShmSizeErrors(t *testing.T) {
+	cases := []string{"0", "1024", "4095", "-1m"}
+	for _, c := range cases {
+		if _, _, err := parse(t, "--shm-size "+c); err == nil {
+			t.Fatalf("expected error for --shm-size %q", c)
+		}
+	}
+}
+
+func TestDecodeContainerConfigTmpfsAndShmSizeRoundTrip(t *testing.T) {
+	_, hostConfig := mustParse(t, "--tmpfs /run:rw,noexec,size=65536k --shm-size 128m")
+
+	wrapper := ContainerConfigWrapper{Config: &Config{Image: "ubuntu"}, HostConfig: hostConfig}
+	b, err := json.Marshal(wrapper)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, decoded, err := DecodeContainerConfig(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Tmpfs["/run"] != "rw,noexec,size=65536k" {
+		t.Fatalf("expected Tmpfs to survive the round trip, got %v", decoded.Tmpfs)
+	}
+	if decoded.ShmSize != 128*1024*1024 {
+		t.Fatalf("expected ShmSize to survive the round trip, got %d", decoded.ShmSize)
+	}
+}
+
+func TestMergeHostConfigTmpfs(t *testing.T) {
+	imageConf := &HostConfig{Tmpfs: map[string]string{"/run": "rw", "/tmp": "noexec"}}
+	userConf := &HostConfig{Tmpfs: map[string]string{"/tmp": "rw,exec"}}
+
+	if err := MergeHostConfig(userConf, imageConf); err != nil {
+		t.Fatal(err)
+	}
+
+	if userConf.Tmpfs["/run"] != "rw" {
+		t.Fatalf("expected image-only /run entry to be preserved, got %q", userConf.Tmpfs["/run"])
+	}
+	if userConf.Tmpfs["/tmp"] != "rw,exec" {
+		t.Fatalf("expected user override of /tmp to win, got %q", userConf.Tmpfs["/tmp"])
+	}
+}
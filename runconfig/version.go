@@ -0,0 +1,152 @@
+package runconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// API version milestones at which the shape of a container-config payload
+// changed. versionHostResourcesOnConfig and versionVolumesFromLinksOnConfig
+// name the first version where the *new* (current) shape is expected;
+// clients on an older version still send the legacy shape.
+const (
+	versionHostResourcesOnConfig    = "1.19"
+	versionVolumesFromLinksOnConfig = "1.20"
+
+	// currentVersion is used by DecodeContainerConfig, which always
+	// targets the current payload shape.
+	currentVersion = "1.99"
+)
+
+// UnsupportedFieldError is returned by DecodeContainerConfigVersion when a
+// payload uses a field it can't accept. For a legacy top-level field sent at
+// or after the API version that moved it under HostConfig, MinVersion is
+// that version, the first one that no longer accepts the field; for a field
+// that is not part of any known payload shape, MinVersion is empty. Fields
+// added in later API versions are not checked against the given version, so
+// an older version's payload that sets them is accepted.
+type UnsupportedFieldError struct {
+	Field      string
+	MinVersion string
+}
+
+func (e *UnsupportedFieldError) Error() string {
+	if e.MinVersion == "" {
+		return fmt.Sprintf("unsupported field: %s", e.Field)
+	}
+	return fmt.Sprintf("%s is not supported in API version %s or later", e.Field, e.MinVersion)
+}
+
+// DecodeContainerConfig decodes a json encoded Config and HostConfig from an
+// io.Reader, as used by the API to create or update containers. It targets
+// the current API version; use DecodeContainerConfigVersion to decode a
+// payload from an older client.
+func DecodeContainerConfig(r io.Reader) (*Config, *HostConfig, error) {
+	return DecodeContainerConfigVersion(r, currentVersion)
+}
+
+// DecodeContainerConfigVersion decodes a json encoded Config and HostConfig
+// from an io.Reader, normalizing fields that moved shape at the given API
+// version:
+//
+//   - before 1.19, Memory/MemorySwap/CpuShares/CpusetCpus lived directly on
+//     the container config instead of under HostConfig
+//   - before 1.20, VolumesFrom/Links lived directly on the container config
+//     instead of under HostConfig
+//
+// A payload that uses a legacy field at or after the version where it was
+// migrated is rejected, since it would otherwise be silently ignored.
+func DecodeContainerConfigVersion(r io.Reader, version string) (*Config, *HostConfig, error) {
+	var w ContainerConfigWrapper
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&w); err != nil {
+		if field, ok := unknownFieldName(err); ok {
+			// A field we don't recognize at all has no minimum version to
+			// report: it isn't a legacy field that moved, it's not part of
+			// any known payload shape.
+			return nil, nil, &UnsupportedFieldError{Field: field}
+		}
+		return nil, nil, err
+	}
+
+	config := w.Config
+	if config == nil {
+		config = &Config{}
+	}
+	hostConfig := w.HostConfig
+	if hostConfig == nil {
+		hostConfig = &HostConfig{}
+	}
+
+	usesLegacyResources := w.MemoryLegacy != nil || w.MemorySwapLegacy != nil ||
+		w.CPUSharesLegacy != nil || w.CpusetCpusLegacy != nil
+	if usesLegacyResources {
+		if !versionLessThan(version, versionHostResourcesOnConfig) {
+			return nil, nil, &UnsupportedFieldError{Field: "Memory/MemorySwap/CpuShares/CpusetCpus on Config", MinVersion: versionHostResourcesOnConfig}
+		}
+		if w.MemoryLegacy != nil {
+			hostConfig.Memory = *w.MemoryLegacy
+		}
+		if w.MemorySwapLegacy != nil {
+			hostConfig.MemorySwap = *w.MemorySwapLegacy
+		}
+		if w.CPUSharesLegacy != nil {
+			hostConfig.CPUShares = *w.CPUSharesLegacy
+		}
+		if w.CpusetCpusLegacy != nil {
+			hostConfig.CpusetCpus = *w.CpusetCpusLegacy
+		}
+	}
+
+	usesLegacyLinks := w.VolumesFromLegacy != "" || len(w.LinksLegacy) > 0
+	if usesLegacyLinks {
+		if !versionLessThan(version, versionVolumesFromLinksOnConfig) {
+			return nil, nil, &UnsupportedFieldError{Field: "VolumesFrom/Links on Config", MinVersion: versionVolumesFromLinksOnConfig}
+		}
+		if w.VolumesFromLegacy != "" {
+			hostConfig.VolumesFrom = strings.Split(w.VolumesFromLegacy, ",")
+		}
+		if len(w.LinksLegacy) > 0 {
+			hostConfig.Links = w.LinksLegacy
+		}
+	}
+
+	return config, hostConfig, nil
+}
+
+// unknownFieldName extracts the offending field name from the error
+// json.Decoder.Decode returns when DisallowUnknownFields rejects a field
+// that isn't part of any known payload shape.
+func unknownFieldName(err error) (string, bool) {
+	const prefix = "json: unknown field "
+	msg := err.Error()
+	if !strings.HasPrefix(msg, prefix) {
+		return "", false
+	}
+	return strings.Trim(msg[len(prefix):], `"`), true
+}
+
+// versionLessThan reports whether v is strictly older than than, comparing
+// dot-separated numeric components (e.g. "1.9" < "1.19" < "1.20").
+func versionLessThan(v, than string) bool {
+	vParts := strings.Split(v, ".")
+	thanParts := strings.Split(than, ".")
+	for i := 0; i < len(vParts) || i < len(thanParts); i++ {
+		var a, b int
+		if i < len(vParts) {
+			a, _ = strconv.Atoi(vParts[i])
+		}
+		if i < len(thanParts) {
+			b, _ = strconv.Atoi(thanParts[i])
+		}
+		if a != b {
+			return a < b
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package runconfig
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestDecodeContainerConfigVersionRejectsUnknownFields(t *testing.T) {
+	payload := `{"Image":"ubuntu","TotallyBogusFutureField":123}`
+
+	_, _, err := DecodeContainerConfigVersion(strings.NewReader(payload), "1.18")
+	if err == nil {
+		t.Fatal("expected an error decoding a payload with an unrecognized field")
+	}
+	ufe, ok := err.(*UnsupportedFieldError)
+	if !ok {
+		t.Fatalf("expected *UnsupportedFieldError, got %T: %v", err, err)
+	}
+	if ufe.Field != "TotallyBogusFutureField" {
+		t.Fatalf("expected the error to identify the offending field, got %q", ufe.Field)
+	}
+	if ufe.MinVersion != "" {
+		t.Fatalf("expected no MinVersion for a genuinely unknown field, got %q", ufe.MinVersion)
+	}
+}
+
+func TestDecodeContainerConfigVersionUpgradesLegacyResources(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/container_config_pre_1_19.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, hostConfig, err := DecodeContainerConfigVersion(bytes.NewReader(b), "1.18")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Image != "ubuntu" {
+		t.Fatalf("expected ubuntu image, got %s", config.Image)
+	}
+	if hostConfig.Memory != 1000 || hostConfig.MemorySwap != 2000 || hostConfig.CPUShares != 512 || hostConfig.CpusetCpus != "0,1" {
+		t.Fatalf("expected legacy resource fields to be migrated, got %+v", hostConfig)
+	}
+}
+
+func TestDecodeContainerConfigVersionRejectsLegacyResourcesAtNewVersion(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/container_config_pre_1_19.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := DecodeContainerConfigVersion(bytes.NewReader(b), "1.19"); err == nil {
+		t.Fatal("expected an error decoding a legacy resources payload at version 1.19")
+	}
+}
+
+func TestDecodeContainerConfigVersionUpgradesLegacyVolumesFromAndLinks(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/container_config_pre_1_20.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, hostConfig, err := DecodeContainerConfigVersion(bytes.NewReader(b), "1.19")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Image != "ubuntu" {
+		t.Fatalf("expected ubuntu image, got %s", config.Image)
+	}
+	if len(hostConfig.VolumesFrom) != 2 || hostConfig.VolumesFrom[0] != "parent" || hostConfig.VolumesFrom[1] != "other:ro" {
+		t.Fatalf("expected VolumesFrom to be split and migrated, got %v", hostConfig.VolumesFrom)
+	}
+	if len(hostConfig.Links) != 1 || hostConfig.Links[0] != "db:db" {
+		t.Fatalf("expected Links to be migrated, got %v", hostConfig.Links)
+	}
+}
+
+func TestDecodeContainerConfigVersionRejectsLegacyLinksAtNewVersion(t *testing.T) {
+	b, err := ioutil.ReadFile("fixtures/container_config_pre_1_20.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := DecodeContainerConfigVersion(bytes.NewReader(b), "1.20"); err == nil {
+		t.Fatal("expected an error decoding a legacy VolumesFrom/Links payload at version 1.20")
+	}
+}
+
+func TestDecodeContainerConfigDefaultsToCurrentVersion(t *testing.T) {
+	for _, f := range []string{
+		"fixtures/container_config_1_14.json",
+		"fixtures/container_config_1_17.json",
+		"fixtures/container_config_1_19.json",
+	} {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := DecodeContainerConfig(bytes.NewReader(b)); err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+	}
+}